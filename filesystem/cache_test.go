@@ -0,0 +1,119 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocator_FindRootDirFrom_CacheAndInvalidate(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	mustMkdirAll(t, sub)
+
+	l := NewLocator()
+
+	// No go.mod yet: the negative result should be cached.
+	if got := l.findRootDirFrom(sub, goModFilename); got != "" {
+		t.Fatalf("findRootDirFrom = %q before go.mod exists, want \"\"", got)
+	}
+
+	writeGoMod(t, root, "module github.com/dekey/cache\n\ngo 1.21\n")
+
+	// Stale cache still reports not-found until invalidated.
+	if got := l.findRootDirFrom(sub, goModFilename); got != "" {
+		t.Fatalf("findRootDirFrom = %q, want stale \"\" before invalidation", got)
+	}
+
+	l.InvalidateCache(root)
+
+	if got := l.findRootDirFrom(sub, goModFilename); got != root {
+		t.Fatalf("findRootDirFrom = %q after invalidation, want %q", got, root)
+	}
+}
+
+func TestLocator_ParseGoMod_CachesResult(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, "module github.com/dekey/cache\n\ngo 1.21\n")
+
+	l := NewLocator()
+	first, err := l.ParseGoMod(dir)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+
+	writeGoMod(t, dir, "module github.com/dekey/changed\n\ngo 1.21\n")
+
+	second, err := l.ParseGoMod(dir)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	if second.ModulePath != first.ModulePath {
+		t.Errorf("ParseGoMod should return the cached result; got %q, want %q", second.ModulePath, first.ModulePath)
+	}
+
+	l.InvalidateCache(dir)
+
+	third, err := l.ParseGoMod(dir)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	if want := "github.com/dekey/changed"; third.ModulePath != want {
+		t.Errorf("ModulePath after invalidation = %q, want %q", third.ModulePath, want)
+	}
+}
+
+func TestResolver_ImportPath_UsesCachedGoMod(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/hot-path\n\ngo 1.21\n")
+
+	pkgDir := filepath.Join(root, "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	// Resolving several files under the same package directory is the
+	// documented hot path (a code generator calling ImportPath per file);
+	// it should not need to re-stat/re-parse go.mod from scratch each time.
+	for i := 0; i < 3; i++ {
+		got, err := r.ImportPath(filepath.Join(pkgDir, "file.go"))
+		if err != nil {
+			t.Fatalf("ImportPath: %v", err)
+		}
+		if want := "github.com/dekey/hot-path/pkg"; got != want {
+			t.Errorf("ImportPath = %q, want %q", got, want)
+		}
+	}
+
+	if _, ok := r.locator.goModCache.Load(root); !ok {
+		t.Errorf("expected go.mod for %q to be cached after resolving under it", root)
+	}
+}
+
+func TestLocator_InvalidateCache_Descendants(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "child")
+	mustMkdirAll(t, child)
+	writeGoMod(t, child, "module github.com/dekey/child\n\ngo 1.21\n")
+
+	l := NewLocator()
+	if _, err := l.ParseGoMod(child); err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	if got := l.findRootDirFrom(child, goModFilename); got != child {
+		t.Fatalf("findRootDirFrom = %q, want %q", got, child)
+	}
+
+	if err := os.RemoveAll(child); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+
+	// Invalidating the parent should drop cached entries for its
+	// descendants too, not just the exact path passed in.
+	l.InvalidateCache(root)
+
+	if got := l.findRootDirFrom(child, goModFilename); got != "" {
+		t.Fatalf("findRootDirFrom = %q after removal+invalidation, want \"\"", got)
+	}
+}
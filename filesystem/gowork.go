@@ -0,0 +1,122 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const goWorkFilename = "go.work"
+
+// ParsedGoWork is the structured result of parsing a go.work file.
+type ParsedGoWork struct {
+	GoVersion string
+	Use       []string // absolute module-root directories named by `use`
+	Replace   []Replace
+}
+
+// FindWorkspaceRoot walks up from the caller skipCaller frames above this
+// call looking for the nearest go.work, the same convention FindRootDir
+// uses for its file.
+func (l *Locator) FindWorkspaceRoot(skipCaller int) (string, error) {
+	_, currentFilepath, _, ok := runtime.Caller(skipCaller)
+	if !ok {
+		return "", fmt.Errorf("%w", ErrFailToGetCallerID)
+	}
+
+	dir := l.findRootDir(currentFilepath, goWorkFilename)
+	if dir == "" {
+		return "", fmt.Errorf(
+			"cannot find workspace root for filepath [%s] %w",
+			currentFilepath,
+			ErrFailToFindRootDir,
+		)
+	}
+
+	return dir, nil
+}
+
+// ParseGoWork parses the go.work file under root, resolving its `use`
+// directives to absolute module-root paths and collecting its workspace-level
+// `replace` directives, which take precedence over any module-level replace
+// when a workspace is active.
+func (l *Locator) ParseGoWork(root string) (*ParsedGoWork, error) {
+	goWorkFilePath := filepath.Join(root, goWorkFilename)
+
+	fileContentBytes, err := os.ReadFile(goWorkFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.ReplaceAll(string(fileContentBytes), "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	parsed := &ParsedGoWork{}
+	blockKeyword := ""
+
+	for i := 0; i < len(lines); i++ {
+		code, _ := splitComment(lines[i])
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+
+		if blockKeyword != "" {
+			if code == ")" {
+				blockKeyword = ""
+				continue
+			}
+			if err := parsed.applyGoWorkDirective(blockKeyword, code, root); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", goWorkFilePath, i+1, err)
+			}
+			continue
+		}
+
+		keyword, rest, isBlockOpen := splitKeyword(code)
+		if keyword == "" {
+			continue
+		}
+
+		switch keyword {
+		case "go":
+			parsed.GoVersion = rest
+		case "use", "replace":
+			if isBlockOpen {
+				blockKeyword = keyword
+				continue
+			}
+			if err := parsed.applyGoWorkDirective(keyword, rest, root); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", goWorkFilePath, i+1, err)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+func (p *ParsedGoWork) applyGoWorkDirective(keyword, rest, root string) error {
+	switch keyword {
+	case "use":
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return fmt.Errorf("malformed use directive %q", rest)
+		}
+		usePath := unquote(fields[0])
+		if !filepath.IsAbs(usePath) {
+			usePath = filepath.Join(root, usePath)
+		}
+		if absPath, err := filepath.Abs(usePath); err == nil {
+			usePath = absPath
+		}
+		p.Use = append(p.Use, filepath.Clean(usePath))
+	case "replace":
+		r, err := parseReplaceDirective(rest)
+		if err != nil {
+			return err
+		}
+		p.Replace = append(p.Replace, r)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGoWork(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, goWorkFilename), []byte(content), 0o644); err != nil {
+		t.Fatalf("write go.work: %v", err)
+	}
+}
+
+func TestParseGoWork(t *testing.T) {
+	root := t.TempDir()
+	mustMkdirAll(t, filepath.Join(root, "moda"))
+	mustMkdirAll(t, filepath.Join(root, "modb"))
+
+	writeGoWork(t, root, `go 1.21
+
+use (
+	./moda
+	./modb
+)
+
+replace github.com/foo/bar => ./local-bar
+`)
+
+	l := NewLocator()
+	parsed, err := l.ParseGoWork(root)
+	if err != nil {
+		t.Fatalf("ParseGoWork: %v", err)
+	}
+
+	if parsed.GoVersion != "1.21" {
+		t.Errorf("GoVersion = %q", parsed.GoVersion)
+	}
+	if len(parsed.Use) != 2 {
+		t.Fatalf("Use = %d entries, want 2", len(parsed.Use))
+	}
+	if want := filepath.Join(root, "moda"); parsed.Use[0] != want {
+		t.Errorf("Use[0] = %q, want %q", parsed.Use[0], want)
+	}
+	if len(parsed.Replace) != 1 || parsed.Replace[0].NewPath != "./local-bar" {
+		t.Errorf("Replace = %+v", parsed.Replace)
+	}
+}
+
+func TestResolver_ImportPath_Workspace(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	workRoot := t.TempDir()
+	modDir := filepath.Join(workRoot, "moda")
+	mustMkdirAll(t, modDir)
+	writeGoMod(t, modDir, "module github.com/dekey/moda\n\ngo 1.21\n")
+
+	writeGoWork(t, workRoot, "go 1.21\n\nuse ./moda\n")
+
+	pkgDir := filepath.Join(modDir, "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/dekey/moda/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+// TestResolver_ImportPath_WorkspaceUsePreservesModuleReplace confirms a
+// `use`d module's own `replace` directive still applies once a go.work
+// exists above it and declares no conflicting replace itself.
+func TestResolver_ImportPath_WorkspaceUsePreservesModuleReplace(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	workRoot := t.TempDir()
+	modDir := filepath.Join(workRoot, "moda")
+	mustMkdirAll(t, modDir)
+	writeGoMod(t, modDir, "module github.com/dekey/moda\n\ngo 1.21\n\nreplace github.com/foo/bar => ./vendor/bar\n")
+
+	pkgDir := filepath.Join(modDir, "vendor", "bar", "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	writeGoWork(t, workRoot, "go 1.21\n\nuse ./moda\n")
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/foo/bar/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+// TestResolver_ImportPath_WorkspaceReplaceOverridesModule confirms a
+// workspace-level replace directive wins over a module-level one for the
+// same target, matching `go list` behavior under GOWORK.
+func TestResolver_ImportPath_WorkspaceReplaceOverridesModule(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	workRoot := t.TempDir()
+	modDir := filepath.Join(workRoot, "moda")
+	mustMkdirAll(t, modDir)
+	writeGoMod(t, modDir, "module github.com/dekey/moda\n\ngo 1.21\n\nreplace github.com/foo/bar => ./module-level-bar\n")
+
+	workspaceBar := filepath.Join(workRoot, "workspace-bar")
+	pkgDir := filepath.Join(workspaceBar, "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	writeGoWork(t, workRoot, "go 1.21\n\nuse ./moda\n\nreplace github.com/foo/bar => ./workspace-bar\n")
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/foo/bar/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
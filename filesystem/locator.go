@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 const (
@@ -20,7 +21,24 @@ var (
 	ErrModulePathNotFound = errors.New("module path not found in go.mod")
 )
 
-type Locator struct{}
+// Locator finds module/file roots by walking up the filesystem tree. The
+// zero value is ready to use; its caches are populated lazily.
+type Locator struct {
+	rootCache  sync.Map // cacheKey -> rootCacheEntry
+	goModCache sync.Map // cleaned root dir -> *ParsedGoMod
+}
+
+// rootCacheEntry memoizes the result of walking up from a directory looking
+// for a given file: either the nearest root that contains it, or a
+// definitive "no root found above this dir" result.
+type rootCacheEntry struct {
+	root  string
+	found bool
+}
+
+func cacheKey(file, dir string) string {
+	return file + "\x00" + dir
+}
 
 func NewLocator() *Locator {
 	return &Locator{}
@@ -55,50 +73,132 @@ func (l *Locator) FindRootDir(file string, skipCaller int) (string, error) {
 }
 
 func (l *Locator) findRootDir(from string, file string) string {
-	dir := filepath.Dir(from)
+	return l.findRootDirFrom(filepath.Dir(from), file)
+}
+
+// findRootDirFrom walks upward from startDir looking for file, memoizing the
+// outcome (found or not) for every directory it visits so repeated lookups
+// under the same subtree are O(1) after the first. The walk stops at the
+// filesystem root (Windows-safe via filepath.VolumeName), $GOPATH,
+// runtime.GOROOT(), or the first directory holding a VCS marker (.git/.hg)
+// that does not itself contain file; directories above it are not searched.
+func (l *Locator) findRootDirFrom(startDir string, file string) string {
+	if entry, ok := l.rootCache.Load(cacheKey(file, startDir)); ok {
+		return entry.(rootCacheEntry).root
+	}
+
 	gopath := filepath.Clean(os.Getenv("GOPATH"))
-	for dir != "/" && dir != gopath {
+	goroot := filepath.Clean(runtime.GOROOT())
+
+	var visited []string
+	result := rootCacheEntry{}
+
+	dir := startDir
+	for {
+		visited = append(visited, dir)
+
 		envFile := filepath.Join(dir, file)
-		if _, err := os.Stat(envFile); os.IsNotExist(err) {
-			dir = filepath.Dir(dir)
-			continue
+		if _, err := os.Stat(envFile); err == nil {
+			result = rootCacheEntry{root: dir, found: true}
+			break
+		}
+
+		if isFilesystemRoot(dir) || dir == gopath || dir == goroot || hasVCSMarker(dir) {
+			break
 		}
-		return dir
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	for _, v := range visited {
+		l.rootCache.Store(cacheKey(file, v), result)
 	}
-	return ""
+
+	return result.root
 }
 
-func (*Locator) ReadModulePath(root string) (string, error) {
-	goModFilePath := filepath.Join(root, goModFilename)
+// InvalidateCache drops cached root-lookup and parsed-go.mod results for
+// path, its descendants, and any entries that previously resolved to path as
+// their root. Long-running tools that create, remove, or edit go.mod files
+// under a directory they already queried should call this so later lookups
+// re-walk and re-parse instead of returning a stale answer.
+func (l *Locator) InvalidateCache(path string) {
+	clean := filepath.Clean(path)
+	prefix := clean + string(filepath.Separator)
+
+	l.rootCache.Range(func(key, value any) bool {
+		entry := value.(rootCacheEntry)
+		dir := strings.TrimPrefix(key.(string), dirPrefixOf(key.(string)))
+		if dir == clean || strings.HasPrefix(dir, prefix) || entry.root == clean {
+			l.rootCache.Delete(key)
+		}
+		return true
+	})
 
-	fileContentBytes, err := os.ReadFile(goModFilePath)
-	if err != nil {
-		return "", err
+	l.goModCache.Range(func(key, _ any) bool {
+		dir := key.(string)
+		if dir == clean || strings.HasPrefix(dir, prefix) {
+			l.goModCache.Delete(key)
+		}
+		return true
+	})
+}
+
+// dirPrefixOf returns everything up to and including the NUL separator in a
+// cacheKey, i.e. the "file\x00" part, so callers can recover the directory.
+func dirPrefixOf(key string) string {
+	idx := strings.IndexByte(key, 0)
+	if idx < 0 {
+		return ""
 	}
+	return key[:idx+1]
+}
 
-	lines := strings.Split(string(fileContentBytes), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			mod := strings.TrimSpace(strings.TrimPrefix(line, "module "))
-			// strip quotes if any
-			mod = strings.Trim(mod, "\"`")
-			// drop trailing .git if present
-			mod = strings.TrimSuffix(mod, ".git")
-
-			return mod, nil
+// isFilesystemRoot reports whether dir is the root of its filesystem,
+// accounting for Windows drive letters (e.g. `C:\`) as well as `/`.
+func isFilesystemRoot(dir string) bool {
+	vol := filepath.VolumeName(dir)
+	return dir == vol+string(filepath.Separator) || dir == vol
+}
+
+// hasVCSMarker reports whether dir contains a .git or .hg marker, which this
+// package treats as an implicit boundary when no file of interest has been
+// found at or below it yet.
+func hasVCSMarker(dir string) bool {
+	for _, marker := range []string{".git", ".hg"} {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
 		}
 	}
-	return "", fmt.Errorf("%w", ErrModulePathNotFound)
+	return false
+}
+
+func (l *Locator) ReadModulePath(root string) (string, error) {
+	parsed, err := l.ParseGoMod(root)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.ModulePath, nil
 }
 
 // RelativePackagePath returns the package path relative to the module root.
 // modRoot is a path from root dir to this project like: `/Users/username/project`
 // fullPath is a full path to package  like `/Users/username/project/pkg/destination`
-// returns relative path to package project/pkg/destination
+// returns relative path to package pkg/destination
+//
+// Deprecated: use Resolver.ImportPath for new code — it also accounts for
+// nested modules and replace directives when modRoot isn't known up front.
+// RelativePackagePath is kept for existing callers that already know
+// modRoot; it delegates its relative-path computation to the same relSlash
+// helper Resolver uses, rather than duplicating filepath.Rel/ToSlash here.
 func (*Locator) RelativePackagePath(modRoot string, fullPath string) (string, error) {
 	slog.Debug("RelativePackagePath", slog.String("modRoot", modRoot), slog.String("fullPath", fullPath))
-	result, err := filepath.Rel(modRoot, fullPath)
+	result, err := relSlash(modRoot, fullPath)
 	if err != nil {
 		return "", err
 	}
@@ -107,5 +207,5 @@ func (*Locator) RelativePackagePath(modRoot string, fullPath string) (string, er
 		slog.String("result", result),
 	)
 
-	return filepath.Dir(result), nil
+	return result, nil
 }
@@ -0,0 +1,93 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleMode is the effective package-resolution strategy a tool should use:
+// Go modules, legacy GOPATH, or an undecided case the caller may treat
+// however it likes (modern `go` defaults this to module mode).
+type ModuleMode int
+
+const (
+	// ModeAuto means DetectMode could not tell module mode from GOPATH mode
+	// apart: GO111MODULE is unset/"auto", no go.mod was found above the
+	// working directory, and the working directory isn't under a
+	// $GOPATH/src tree either.
+	ModeAuto ModuleMode = iota
+	ModeModule
+	ModeGOPATH
+)
+
+func (m ModuleMode) String() string {
+	switch m {
+	case ModeModule:
+		return "module"
+	case ModeGOPATH:
+		return "gopath"
+	default:
+		return "auto"
+	}
+}
+
+// DetectMode reports which package-resolution strategy applies to the
+// current process, following the same precedence `go` itself uses: an
+// explicit GO111MODULE=on/off wins outright; otherwise the nearest go.mod
+// above the working directory selects module mode, and failing that, the
+// working directory being under $GOPATH/src selects GOPATH mode.
+func (l *Locator) DetectMode() (ModuleMode, error) {
+	switch strings.ToLower(os.Getenv("GO111MODULE")) {
+	case "off":
+		return ModeGOPATH, nil
+	case "on":
+		return ModeModule, nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ModeAuto, err
+	}
+
+	if root := l.findRootDirFrom(wd, goModFilename); root != "" {
+		return ModeModule, nil
+	}
+
+	if inGopathSrc(wd) {
+		return ModeGOPATH, nil
+	}
+
+	return ModeAuto, nil
+}
+
+// PackagePathAuto returns the import path for fsPath, dispatching to
+// module-aware or GOPATH-relative resolution based on DetectMode so callers
+// that still need to support pre-modules layouts don't have to hand-code the
+// branch themselves.
+func (l *Locator) PackagePathAuto(fsPath string) (string, error) {
+	mode, err := l.DetectMode()
+	if err != nil {
+		return "", err
+	}
+
+	resolver := &Resolver{locator: l}
+	if mode == ModeGOPATH {
+		return resolver.GOPATHImportPath(fsPath)
+	}
+
+	return resolver.ImportPath(fsPath)
+}
+
+func inGopathSrc(dir string) bool {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		return false
+	}
+	for _, gp := range filepath.SplitList(gopath) {
+		if isWithin(filepath.Join(gp, "src"), dir) {
+			return true
+		}
+	}
+	return false
+}
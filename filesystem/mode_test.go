@@ -0,0 +1,132 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectMode_ExplicitGO111MODULE(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	t.Run("off", func(t *testing.T) {
+		t.Setenv("GO111MODULE", "off")
+		l := NewLocator()
+		mode, err := l.DetectMode()
+		if err != nil {
+			t.Fatalf("DetectMode: %v", err)
+		}
+		if mode != ModeGOPATH {
+			t.Errorf("mode = %v, want %v", mode, ModeGOPATH)
+		}
+	})
+
+	t.Run("on", func(t *testing.T) {
+		t.Setenv("GO111MODULE", "ON")
+		l := NewLocator()
+		mode, err := l.DetectMode()
+		if err != nil {
+			t.Fatalf("DetectMode: %v", err)
+		}
+		if mode != ModeModule {
+			t.Errorf("mode = %v, want %v", mode, ModeModule)
+		}
+	})
+}
+
+func TestDetectMode_AutoWithGoMod(t *testing.T) {
+	t.Setenv("GO111MODULE", "")
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/auto\n\ngo 1.21\n")
+
+	sub := filepath.Join(root, "pkg")
+	mustMkdirAll(t, sub)
+	defer chdir(t, sub)()
+
+	l := NewLocator()
+	mode, err := l.DetectMode()
+	if err != nil {
+		t.Fatalf("DetectMode: %v", err)
+	}
+	if mode != ModeModule {
+		t.Errorf("mode = %v, want %v", mode, ModeModule)
+	}
+}
+
+func TestDetectMode_AutoUnderGOPATH(t *testing.T) {
+	t.Setenv("GO111MODULE", "")
+
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath)
+
+	dir := filepath.Join(gopath, "src", "github.com", "dekey", "legacy")
+	mustMkdirAll(t, dir)
+	defer chdir(t, dir)()
+
+	l := NewLocator()
+	mode, err := l.DetectMode()
+	if err != nil {
+		t.Fatalf("DetectMode: %v", err)
+	}
+	if mode != ModeGOPATH {
+		t.Errorf("mode = %v, want %v", mode, ModeGOPATH)
+	}
+}
+
+func TestDetectMode_AutoNeither(t *testing.T) {
+	t.Setenv("GO111MODULE", "")
+	t.Setenv("GOPATH", "")
+
+	dir := t.TempDir()
+	defer chdir(t, dir)()
+
+	l := NewLocator()
+	mode, err := l.DetectMode()
+	if err != nil {
+		t.Fatalf("DetectMode: %v", err)
+	}
+	if mode != ModeAuto {
+		t.Errorf("mode = %v, want %v", mode, ModeAuto)
+	}
+}
+
+func TestPackagePathAuto_GOPATHMode(t *testing.T) {
+	t.Setenv("GO111MODULE", "off")
+
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath)
+
+	pkgDir := filepath.Join(gopath, "src", "github.com", "dekey", "legacy")
+	mustMkdirAll(t, pkgDir)
+
+	l := NewLocator()
+	got, err := l.PackagePathAuto(pkgDir)
+	if err != nil {
+		t.Fatalf("PackagePathAuto: %v", err)
+	}
+	if want := "github.com/dekey/legacy"; got != want {
+		t.Errorf("PackagePathAuto = %q, want %q", got, want)
+	}
+}
+
+func TestPackagePathAuto_ModuleMode(t *testing.T) {
+	t.Setenv("GO111MODULE", "")
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/auto\n\ngo 1.21\n")
+
+	pkgDir := filepath.Join(root, "pkg")
+	mustMkdirAll(t, pkgDir)
+	defer chdir(t, pkgDir)()
+
+	l := NewLocator()
+	got, err := l.PackagePathAuto(pkgDir)
+	if err != nil {
+		t.Fatalf("PackagePathAuto: %v", err)
+	}
+	if want := "github.com/dekey/auto/pkg"; got != want {
+		t.Errorf("PackagePathAuto = %q, want %q", got, want)
+	}
+}
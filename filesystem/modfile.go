@@ -0,0 +1,261 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Require is a parsed `require` directive.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// Replace is a parsed `replace` directive. NewVersion is empty when the
+// replacement target is a local filesystem path rather than a module version.
+type Replace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// Exclude is a parsed `exclude` directive.
+type Exclude struct {
+	Path    string
+	Version string
+}
+
+// Retract is a parsed `retract` directive. Low and High are equal for a
+// single retracted version rather than a range.
+type Retract struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// ParsedGoMod is the structured result of parsing a go.mod file.
+type ParsedGoMod struct {
+	ModulePath string
+	GoVersion  string
+	Require    []Require
+	Replace    []Replace
+	Exclude    []Exclude
+	Retract    []Retract
+}
+
+// ParseGoMod parses the go.mod file under root into structured data covering
+// the module path, Go version, and the require/replace/exclude/retract
+// directives. It is a self-contained tokenizer rather than a full
+// specification-compliant parser, but it understands the block forms
+// (`require (\n ... \n)`), the `module (\n ... \n)` form, `\r\n` line endings,
+// and trailing `// indirect` / retraction-reason comments.
+//
+// Results are cached per root, since callers like Resolver.ImportPath parse
+// the same go.mod repeatedly on hot paths (e.g. once per generated file).
+// Call Locator.InvalidateCache(root) after editing a go.mod a Locator has
+// already parsed.
+func (l *Locator) ParseGoMod(root string) (*ParsedGoMod, error) {
+	clean := filepath.Clean(root)
+	if cached, ok := l.goModCache.Load(clean); ok {
+		return cached.(*ParsedGoMod), nil
+	}
+
+	parsed, err := l.parseGoModUncached(clean)
+	if err != nil {
+		return nil, err
+	}
+
+	l.goModCache.Store(clean, parsed)
+	return parsed, nil
+}
+
+func (l *Locator) parseGoModUncached(root string) (*ParsedGoMod, error) {
+	goModFilePath := filepath.Join(root, goModFilename)
+
+	fileContentBytes, err := os.ReadFile(goModFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.ReplaceAll(string(fileContentBytes), "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	parsed := &ParsedGoMod{}
+	blockKeyword := ""
+
+	for i := 0; i < len(lines); i++ {
+		code, comment := splitComment(lines[i])
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+
+		if blockKeyword != "" {
+			if code == ")" {
+				blockKeyword = ""
+				continue
+			}
+			if err := parsed.applyDirective(blockKeyword, code, comment); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", goModFilePath, i+1, err)
+			}
+			continue
+		}
+
+		keyword, rest, isBlockOpen := splitKeyword(code)
+		if keyword == "" {
+			continue
+		}
+
+		switch keyword {
+		case "module":
+			if isBlockOpen {
+				i = parsed.readModuleBlock(lines, i+1)
+				continue
+			}
+			parsed.ModulePath = unquote(rest)
+		case "go":
+			parsed.GoVersion = rest
+		case "require", "replace", "exclude", "retract":
+			if isBlockOpen {
+				blockKeyword = keyword
+				continue
+			}
+			if err := parsed.applyDirective(keyword, rest, comment); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", goModFilePath, i+1, err)
+			}
+		}
+	}
+
+	if parsed.ModulePath == "" {
+		return nil, fmt.Errorf("%w", ErrModulePathNotFound)
+	}
+
+	return parsed, nil
+}
+
+// readModuleBlock reads the module path out of a `module (\n ... \n)` block
+// and returns the index of the line it stopped on.
+func (p *ParsedGoMod) readModuleBlock(lines []string, from int) int {
+	for j := from; j < len(lines); j++ {
+		code, _ := splitComment(lines[j])
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		if code == ")" {
+			return j
+		}
+		p.ModulePath = unquote(code)
+		return j
+	}
+	return len(lines) - 1
+}
+
+func (p *ParsedGoMod) applyDirective(keyword, rest, comment string) error {
+	switch keyword {
+	case "require":
+		fields := strings.Fields(rest)
+		if len(fields) < 2 {
+			return fmt.Errorf("malformed require directive %q", rest)
+		}
+		p.Require = append(p.Require, Require{
+			Path:     unquote(fields[0]),
+			Version:  fields[1],
+			Indirect: strings.Contains(comment, "indirect"),
+		})
+	case "exclude":
+		fields := strings.Fields(rest)
+		if len(fields) < 2 {
+			return fmt.Errorf("malformed exclude directive %q", rest)
+		}
+		p.Exclude = append(p.Exclude, Exclude{
+			Path:    unquote(fields[0]),
+			Version: fields[1],
+		})
+	case "replace":
+		r, err := parseReplaceDirective(rest)
+		if err != nil {
+			return err
+		}
+		p.Replace = append(p.Replace, r)
+	case "retract":
+		low, high, ok := parseRetractRange(rest)
+		if !ok {
+			return fmt.Errorf("malformed retract directive %q", rest)
+		}
+		p.Retract = append(p.Retract, Retract{Low: low, High: high, Rationale: comment})
+	}
+	return nil
+}
+
+// parseReplaceDirective parses the body of a `replace` directive (everything
+// after the keyword), shared by go.mod and go.work parsing since both use
+// the identical `old [oldver] => new [newver]` syntax.
+func parseReplaceDirective(rest string) (Replace, error) {
+	oldSide, newSide, ok := strings.Cut(rest, "=>")
+	if !ok {
+		return Replace{}, fmt.Errorf("malformed replace directive %q", rest)
+	}
+	oldFields := strings.Fields(oldSide)
+	newFields := strings.Fields(newSide)
+	if len(oldFields) == 0 || len(newFields) == 0 {
+		return Replace{}, fmt.Errorf("malformed replace directive %q", rest)
+	}
+	r := Replace{OldPath: unquote(oldFields[0]), NewPath: unquote(newFields[0])}
+	if len(oldFields) > 1 {
+		r.OldVersion = oldFields[1]
+	}
+	if len(newFields) > 1 {
+		r.NewVersion = newFields[1]
+	}
+	return r, nil
+}
+
+func parseRetractRange(rest string) (low, high string, ok bool) {
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") {
+		parts := strings.Split(strings.Trim(rest, "[]"), ",")
+		if len(parts) != 2 {
+			return "", "", false
+		}
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+	}
+	fields := strings.Fields(rest)
+	if len(fields) != 1 {
+		return "", "", false
+	}
+	return fields[0], fields[0], true
+}
+
+// splitKeyword splits a go.mod directive line into its leading keyword and
+// the remainder of the line, reporting whether the remainder opens a block
+// (i.e. the line is exactly "keyword (").
+func splitKeyword(code string) (keyword, rest string, isBlockOpen bool) {
+	fields := strings.SplitN(code, " ", 2)
+	keyword = fields[0]
+	if len(fields) == 2 {
+		rest = strings.TrimSpace(fields[1])
+	}
+	if rest == "(" {
+		return keyword, "", true
+	}
+	return keyword, rest, false
+}
+
+// splitComment splits a go.mod line into its code and `//` comment parts.
+func splitComment(line string) (code, comment string) {
+	idx := strings.Index(line, "//")
+	if idx < 0 {
+		return line, ""
+	}
+	return line[:idx], strings.TrimSpace(line[idx+2:])
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.Trim(s, "\"`")
+}
@@ -0,0 +1,152 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGoMod(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, goModFilename), []byte(content), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+}
+
+func mustMkdirAll(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatalf("MkdirAll(%s): %v", path, err)
+	}
+}
+
+func chdir(t *testing.T, dir string) func() {
+	t.Helper()
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir(%s): %v", dir, err)
+	}
+	return func() {
+		_ = os.Chdir(prev)
+	}
+}
+
+func TestParseGoMod_Basic(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, `module github.com/dekey/go-pkg
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/mod v0.14.0 // indirect
+)
+
+exclude github.com/bad/dep v1.0.0
+
+replace github.com/old/mod => ../local/mod
+
+replace github.com/pinned/mod v1.0.0 => github.com/pinned/mod v1.0.1
+
+retract v1.2.0
+
+retract [v1.0.0, v1.1.0] // known data loss bug
+`)
+
+	l := NewLocator()
+	parsed, err := l.ParseGoMod(dir)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+
+	if parsed.ModulePath != "github.com/dekey/go-pkg" {
+		t.Errorf("ModulePath = %q", parsed.ModulePath)
+	}
+	if parsed.GoVersion != "1.21" {
+		t.Errorf("GoVersion = %q", parsed.GoVersion)
+	}
+
+	if len(parsed.Require) != 2 {
+		t.Fatalf("Require = %d entries, want 2", len(parsed.Require))
+	}
+	if parsed.Require[0].Indirect {
+		t.Errorf("first require should not be indirect: %+v", parsed.Require[0])
+	}
+	if !parsed.Require[1].Indirect {
+		t.Errorf("second require should be indirect: %+v", parsed.Require[1])
+	}
+
+	if len(parsed.Exclude) != 1 || parsed.Exclude[0].Path != "github.com/bad/dep" {
+		t.Errorf("Exclude = %+v", parsed.Exclude)
+	}
+
+	if len(parsed.Replace) != 2 {
+		t.Fatalf("Replace = %d entries, want 2", len(parsed.Replace))
+	}
+	if parsed.Replace[0].NewPath != "../local/mod" || parsed.Replace[0].NewVersion != "" {
+		t.Errorf("local replace = %+v", parsed.Replace[0])
+	}
+	if parsed.Replace[1].NewVersion != "v1.0.1" {
+		t.Errorf("versioned replace = %+v", parsed.Replace[1])
+	}
+
+	if len(parsed.Retract) != 2 {
+		t.Fatalf("Retract = %d entries, want 2", len(parsed.Retract))
+	}
+	if parsed.Retract[0].Low != parsed.Retract[0].High {
+		t.Errorf("single-version retract should have Low == High: %+v", parsed.Retract[0])
+	}
+	if parsed.Retract[1].Low != "v1.0.0" || parsed.Retract[1].High != "v1.1.0" {
+		t.Errorf("range retract = %+v", parsed.Retract[1])
+	}
+	if !strings.Contains(parsed.Retract[1].Rationale, "data loss") {
+		t.Errorf("Rationale = %q", parsed.Retract[1].Rationale)
+	}
+}
+
+func TestParseGoMod_ModuleBlockAndCRLF(t *testing.T) {
+	dir := t.TempDir()
+	content := "module (\r\n\tgithub.com/dekey/go-pkg\r\n)\r\n\r\ngo 1.21\r\n"
+	writeGoMod(t, dir, content)
+
+	l := NewLocator()
+	parsed, err := l.ParseGoMod(dir)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	if parsed.ModulePath != "github.com/dekey/go-pkg" {
+		t.Errorf("ModulePath = %q", parsed.ModulePath)
+	}
+	if parsed.GoVersion != "1.21" {
+		t.Errorf("GoVersion = %q", parsed.GoVersion)
+	}
+}
+
+func TestReadModulePath(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, "module github.com/dekey/go-pkg\n\ngo 1.21\n")
+
+	l := NewLocator()
+	got, err := l.ReadModulePath(dir)
+	if err != nil {
+		t.Fatalf("ReadModulePath: %v", err)
+	}
+	if got != "github.com/dekey/go-pkg" {
+		t.Errorf("ReadModulePath = %q", got)
+	}
+}
+
+func TestParseGoMod_MissingModulePath(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, "go 1.21\n")
+
+	l := NewLocator()
+	if _, err := l.ParseGoMod(dir); !errors.Is(err, ErrModulePathNotFound) {
+		t.Errorf("err = %v, want ErrModulePathNotFound", err)
+	}
+}
@@ -0,0 +1,342 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resolver maps filesystem paths to fully-qualified Go import paths,
+// mirroring the behavior of `go env GOMOD` plus module-path resolution.
+type Resolver struct {
+	locator *Locator
+}
+
+// NewResolver returns a Resolver backed by a fresh Locator.
+func NewResolver() *Resolver {
+	return &Resolver{locator: NewLocator()}
+}
+
+// ImportPath returns the fully-qualified import path for fsPath, which may be
+// either a directory or a file and may be absolute or relative to the
+// current working directory.
+//
+// If fsPath lives under an active go.work workspace, it resolves against
+// that workspace first (workspace `replace` directives override module-level
+// ones, matching `go list` under GOWORK). Otherwise it walks upward from
+// fsPath to find the nearest go.mod (the innermost enclosing module, for
+// trees with nested modules), and joins the module path with the path
+// relative to that module's root using forward slashes. If an enclosing
+// module declares a local `replace` directive whose target directory
+// contains fsPath, the import path is computed against the replaced (old)
+// path instead, matching how the rest of the module graph would resolve it.
+// If no go.mod is found anywhere above fsPath, it falls back to a
+// GOPATH-relative import path.
+func (r *Resolver) ImportPath(fsPath string) (string, error) {
+	targetDir, err := normalizeTargetDir(fsPath)
+	if err != nil {
+		return "", err
+	}
+
+	if workspacePath, ok, err := r.resolveViaWorkspace(targetDir); err != nil {
+		return "", err
+	} else if ok {
+		return workspacePath, nil
+	}
+
+	if replacedPath, ok, err := r.resolveViaReplace(targetDir); err != nil {
+		return "", err
+	} else if ok {
+		return replacedPath, nil
+	}
+
+	modRoot := r.locator.findRootDirFrom(targetDir, goModFilename)
+	if modRoot == "" {
+		return r.gopathImportPath(targetDir)
+	}
+
+	parsed, err := r.locator.ParseGoMod(modRoot)
+	if err != nil {
+		return "", err
+	}
+
+	return joinImportPath(parsed.ModulePath, modRoot, targetDir)
+}
+
+// resolveViaWorkspace checks whether targetDir falls under an active go.work
+// workspace. A workspace-level `replace` pointing a local path at targetDir
+// wins first (it overrides module-level replace); otherwise, if targetDir
+// lives under one of the workspace's `use`d module roots, that module's own
+// `replace` directives are checked before falling back to a plain import
+// path against its go.mod, so a module-level replace still applies once a
+// go.work exists above it.
+func (r *Resolver) resolveViaWorkspace(targetDir string) (string, bool, error) {
+	workRoot := r.locator.findRootDirFrom(targetDir, goWorkFilename)
+	if workRoot == "" {
+		return "", false, nil
+	}
+
+	work, err := r.locator.ParseGoWork(workRoot)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, rep := range work.Replace {
+		if rep.NewVersion != "" {
+			continue // not a local path replacement
+		}
+		replTarget := rep.NewPath
+		if !filepath.IsAbs(replTarget) {
+			replTarget = filepath.Join(workRoot, replTarget)
+		}
+		if resolved, err := filepath.EvalSymlinks(replTarget); err == nil {
+			replTarget = resolved
+		}
+		if !isWithin(replTarget, targetDir) {
+			continue
+		}
+		importPath, err := joinImportPath(rep.OldPath, replTarget, targetDir)
+		if err != nil {
+			return "", false, err
+		}
+		return importPath, true, nil
+	}
+
+	bestUse := ""
+	for _, use := range work.Use {
+		if !isWithin(use, targetDir) {
+			continue
+		}
+		if len(use) > len(bestUse) {
+			bestUse = use
+		}
+	}
+	if bestUse == "" {
+		return "", false, nil
+	}
+
+	parsed, err := r.locator.ParseGoMod(bestUse)
+	if err != nil {
+		return "", false, err
+	}
+
+	if matchTarget, replImportPath, err := bestLocalReplace(bestUse, parsed.Replace, targetDir); err != nil {
+		return "", false, err
+	} else if matchTarget != "" {
+		return replImportPath, true, nil
+	}
+
+	importPath, err := joinImportPath(parsed.ModulePath, bestUse, targetDir)
+	if err != nil {
+		return "", false, err
+	}
+	return importPath, true, nil
+}
+
+// resolveViaReplace looks for a `replace` directive, declared by either an
+// ancestor module of targetDir or the module the process is running from
+// (os.Getwd()), whose local-path target contains targetDir. The replacing
+// module is commonly a sibling of the replaced directory (e.g. a
+// main-project go.mod replacing `../bar-local`), not an ancestor of it, so
+// both candidate sets are searched; the most specific (longest) local-path
+// match wins.
+func (r *Resolver) resolveViaReplace(targetDir string) (string, bool, error) {
+	candidates := r.locator.goModAncestors(filepath.Dir(targetDir))
+	if wd, err := os.Getwd(); err == nil {
+		for _, dir := range r.locator.goModAncestors(wd) {
+			if !containsDir(candidates, dir) {
+				candidates = append(candidates, dir)
+			}
+		}
+	}
+
+	bestMatch := ""
+	bestImportPath := ""
+
+	for _, dir := range candidates {
+		parsed, err := r.locator.ParseGoMod(dir)
+		if err != nil {
+			return "", false, err
+		}
+		matchTarget, importPath, err := bestLocalReplace(dir, parsed.Replace, targetDir)
+		if err != nil {
+			return "", false, err
+		}
+		if len(matchTarget) <= len(bestMatch) {
+			continue
+		}
+		bestMatch = matchTarget
+		bestImportPath = importPath
+	}
+
+	return bestImportPath, bestMatch != "", nil
+}
+
+// bestLocalReplace scans replaces (declared by a go.mod/go.work rooted at
+// dir) for the most specific local-path `replace` directive whose target
+// contains targetDir, returning its resolved target directory and the
+// import path targetDir should resolve to. matchTarget is empty if no local
+// replace covers targetDir.
+func bestLocalReplace(dir string, replaces []Replace, targetDir string) (matchTarget, importPath string, err error) {
+	for _, rep := range replaces {
+		if rep.NewVersion != "" {
+			continue // not a local path replacement
+		}
+		replTarget := rep.NewPath
+		if !filepath.IsAbs(replTarget) {
+			replTarget = filepath.Join(dir, replTarget)
+		}
+		if resolved, err := filepath.EvalSymlinks(replTarget); err == nil {
+			replTarget = resolved
+		}
+		if !isWithin(replTarget, targetDir) {
+			continue
+		}
+		if len(replTarget) <= len(matchTarget) {
+			continue
+		}
+		ip, err := joinImportPath(rep.OldPath, replTarget, targetDir)
+		if err != nil {
+			return "", "", err
+		}
+		matchTarget = replTarget
+		importPath = ip
+	}
+	return matchTarget, importPath, nil
+}
+
+func containsDir(dirs []string, dir string) bool {
+	for _, d := range dirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// goModAncestors returns every directory from start upward that contains a
+// go.mod, nearest first. Each step is one findRootDirFrom walk and ends at
+// the same boundaries (filesystem root, $GOPATH, GOROOT, VCS marker); the
+// list ends at the first step that finds no go.mod. Each step reuses
+// findRootDirFrom's cache, so repeated calls under the same subtree only pay
+// for the filesystem walk once.
+func (l *Locator) goModAncestors(start string) []string {
+	var dirs []string
+	dir := start
+	for {
+		root := l.findRootDirFrom(dir, goModFilename)
+		if root == "" {
+			break
+		}
+		dirs = append(dirs, root)
+
+		parent := filepath.Dir(root)
+		if parent == root {
+			break
+		}
+		dir = parent
+	}
+	return dirs
+}
+
+// normalizeTargetDir resolves fsPath to an absolute directory, with symlinks
+// resolved when fsPath exists; a missing fsPath is kept as given, without
+// symlink resolution. If fsPath names a file, its containing directory is
+// used instead. fsPath
+// need not exist yet (code generators call this for a file they're about to
+// write), so a missing path is still treated as a file when it has an
+// extension, rather than assumed to be a directory.
+func normalizeTargetDir(fsPath string) (string, error) {
+	absPath, err := filepath.Abs(fsPath)
+	if err != nil {
+		return "", err
+	}
+	if resolved, symErr := filepath.EvalSymlinks(absPath); symErr == nil {
+		absPath = resolved
+	}
+
+	info, statErr := os.Stat(absPath)
+	switch {
+	case statErr == nil:
+		if !info.IsDir() {
+			absPath = filepath.Dir(absPath)
+		}
+	case filepath.Ext(absPath) != "":
+		absPath = filepath.Dir(absPath)
+	}
+
+	return absPath, nil
+}
+
+// GOPATHImportPath returns the GOPATH-relative import path for fsPath,
+// stripping the `$GOPATH/src` prefix directly rather than consulting any
+// go.mod. Callers that have already decided GOPATH mode applies (see
+// Locator.DetectMode) use this to skip the module-resolution path entirely.
+func (r *Resolver) GOPATHImportPath(fsPath string) (string, error) {
+	targetDir, err := normalizeTargetDir(fsPath)
+	if err != nil {
+		return "", err
+	}
+	return r.gopathImportPath(targetDir)
+}
+
+// isWithin reports whether target is root itself or a descendant of root.
+func isWithin(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// relSlash returns target's path relative to root, with forward slashes
+// regardless of OS. It is the single place both joinImportPath and
+// Locator.RelativePackagePath compute a relative package path, so the two
+// stay consistent.
+func relSlash(root, target string) (string, error) {
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// joinImportPath joins modulePath with targetDir's path relative to root,
+// using forward slashes regardless of OS.
+func joinImportPath(modulePath, root, targetDir string) (string, error) {
+	rel, err := relSlash(root, targetDir)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return modulePath, nil
+	}
+	return modulePath + "/" + rel, nil
+}
+
+// gopathImportPath falls back to a GOPATH-relative import path when no
+// go.mod is found above targetDir.
+func (r *Resolver) gopathImportPath(targetDir string) (string, error) {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		return "", fmt.Errorf("%w", ErrModulePathNotFound)
+	}
+
+	for _, gp := range filepath.SplitList(gopath) {
+		srcRoot := filepath.Join(gp, "src")
+		if !isWithin(srcRoot, targetDir) {
+			continue
+		}
+		rel, err := filepath.Rel(srcRoot, targetDir)
+		if err != nil {
+			return "", err
+		}
+		if rel == "." {
+			return "", fmt.Errorf("%w", ErrModulePathNotFound)
+		}
+		return filepath.ToSlash(rel), nil
+	}
+
+	return "", fmt.Errorf("%w", ErrModulePathNotFound)
+}
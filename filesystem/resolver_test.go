@@ -0,0 +1,117 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolver_ImportPath_NestedModule(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/outer\n\ngo 1.21\n")
+
+	inner := filepath.Join(root, "vendor", "inner")
+	mustMkdirAll(t, inner)
+	writeGoMod(t, inner, "module github.com/dekey/inner\n\ngo 1.21\n")
+
+	pkgDir := filepath.Join(inner, "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/dekey/inner/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+func TestResolver_ImportPath_ReplaceNestedInOwnTree(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/main\n\ngo 1.21\n\nreplace github.com/foo/bar => ./vendor/bar\n")
+
+	pkgDir := filepath.Join(root, "vendor", "bar", "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/foo/bar/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+// TestResolver_ImportPath_ReplaceSiblingDirectory covers the primary use
+// case named in the request: the replacing module's go.mod is a sibling of
+// the replaced directory, not an ancestor of it, so the replace-declaring
+// module can only be found via the working directory, not by walking up
+// from the target.
+func TestResolver_ImportPath_ReplaceSiblingDirectory(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	workspace := t.TempDir()
+
+	mainDir := filepath.Join(workspace, "main-project")
+	mustMkdirAll(t, mainDir)
+	writeGoMod(t, mainDir, "module github.com/dekey/main\n\ngo 1.21\n\nreplace github.com/foo/bar => ../bar-local\n")
+
+	barLocal := filepath.Join(workspace, "bar-local")
+	pkgDir := filepath.Join(barLocal, "pkg")
+	mustMkdirAll(t, pkgDir)
+	// bar-local is its own module under an unrelated path; main-project's
+	// replace directive should still take precedence for code under it.
+	writeGoMod(t, barLocal, "module example.com/bar-local-fork\n\ngo 1.21\n")
+
+	defer chdir(t, mainDir)()
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/foo/bar/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+func TestResolver_ImportPath_NonExistentFile(t *testing.T) {
+	t.Setenv("GOPATH", "")
+
+	root := t.TempDir()
+	writeGoMod(t, root, "module github.com/dekey/gen\n\ngo 1.21\n")
+
+	pkgDir := filepath.Join(root, "pkg")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	got, err := r.ImportPath(filepath.Join(pkgDir, "generated.go"))
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/dekey/gen/pkg"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}
+
+func TestResolver_ImportPath_GOPATHFallback(t *testing.T) {
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath)
+
+	pkgDir := filepath.Join(gopath, "src", "github.com", "dekey", "legacy")
+	mustMkdirAll(t, pkgDir)
+
+	r := NewResolver()
+	got, err := r.ImportPath(pkgDir)
+	if err != nil {
+		t.Fatalf("ImportPath: %v", err)
+	}
+	if want := "github.com/dekey/legacy"; got != want {
+		t.Errorf("ImportPath = %q, want %q", got, want)
+	}
+}